@@ -0,0 +1,60 @@
+package onvif
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// WithAuth returns a copy of device configured to authenticate every SOAP
+// request with a WS-Security UsernameToken built from user and pass.
+// Existing callers that never set credentials are unaffected, since the
+// security header is simply omitted when User is empty.
+func (device Device) WithAuth(user, pass string) Device {
+	device.User = user
+	device.Password = pass
+	return device
+}
+
+// SecurityHeader builds the wsse:Security SOAP header carrying a
+// UsernameToken with a PasswordDigest, per the WS-Security UsernameToken
+// Profile used by ONVIF devices. It returns "" when the device has no
+// credentials configured.
+func (device Device) SecurityHeader() string {
+	if device.User == "" {
+		return ""
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return ""
+	}
+
+	created := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	digest := passwordDigest(nonce, created, device.Password)
+
+	return fmt.Sprintf(`<wsse:Security xmlns:wsse="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd" xmlns:wsu="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd">
+		<wsse:UsernameToken>
+			<wsse:Username>%s</wsse:Username>
+			<wsse:Password Type="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest">%s</wsse:Password>
+			<wsse:Nonce EncodingType="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary">%s</wsse:Nonce>
+			<wsu:Created>%s</wsu:Created>
+		</wsse:UsernameToken>
+	</wsse:Security>`, device.User, digest, base64.StdEncoding.EncodeToString(nonce), created)
+}
+
+// passwordDigest computes the WS-Security UsernameToken PasswordDigest:
+// Base64(SHA1(nonce + created + password)), per the WS-Security
+// UsernameToken Profile 1.0. Split out from SecurityHeader so the digest
+// math can be tested against known nonce/created/password/digest fixtures
+// without depending on the random nonce and current time SecurityHeader
+// generates.
+func passwordDigest(nonce []byte, created, password string) string {
+	hash := sha1.New()
+	hash.Write(nonce)
+	hash.Write([]byte(created))
+	hash.Write([]byte(password))
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
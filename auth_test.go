@@ -0,0 +1,66 @@
+package onvif
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestPasswordDigest(t *testing.T) {
+	cases := []struct {
+		name     string
+		nonceB64 string
+		created  string
+		password string
+		want     string
+	}{
+		{
+			name:     "WS-Security UsernameToken Profile 1.0 example",
+			nonceB64: "LKqI6G/AikKCQrN0zqZFlg==",
+			created:  "2010-12-21T15:35:06.276Z",
+			password: "sampleP@ssw0rd",
+			want:     "L0Vfk/j/ruJuge/8ZT3CGgxg52w=",
+		},
+		{
+			name:     "different nonce, created and password",
+			nonceB64: "PDSKbAdSWhVUQf0mJgKyTQ==",
+			created:  "2017-06-06T08:01:30.000Z",
+			password: "admin123",
+			want:     "VEe1dfqnmXnYUvgVKNTdMt0LeZE=",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			nonce, err := base64.StdEncoding.DecodeString(c.nonceB64)
+			if err != nil {
+				t.Fatalf("failed to decode nonce fixture: %v", err)
+			}
+
+			got := passwordDigest(nonce, c.created, c.password)
+			if got != c.want {
+				t.Errorf("passwordDigest() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSecurityHeaderEmptyWithoutCredentials(t *testing.T) {
+	device := Device{}
+	if header := device.SecurityHeader(); header != "" {
+		t.Errorf("SecurityHeader() with no credentials = %q, want empty string", header)
+	}
+}
+
+func TestSecurityHeaderContainsUsername(t *testing.T) {
+	device := Device{}.WithAuth("admin", "admin123")
+
+	header := device.SecurityHeader()
+	if header == "" {
+		t.Fatal("SecurityHeader() with credentials set = empty string, want non-empty")
+	}
+
+	if want := "<wsse:Username>admin</wsse:Username>"; !strings.Contains(header, want) {
+		t.Errorf("SecurityHeader() = %q, want it to contain %q", header, want)
+	}
+}
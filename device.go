@@ -1,71 +1,92 @@
 package onvif
 
 import (
-	"encoding/json"
-	"fmt"
+	"encoding/xml"
+	"reflect"
 	"strings"
+
+	"github.com/kcsraju/go-onvif/services"
 )
 
+const tdsNamespace = "tds"
+
+func init() {
+	services.Register(tdsNamespace, "GetDeviceInformation", &getDeviceInformationRequest{}, &DeviceInformation{})
+	services.Register(tdsNamespace, "GetSystemDateAndTime", &getSystemDateAndTimeRequest{}, &getSystemDateAndTimeResponse{})
+	services.Register(tdsNamespace, "GetDiscoveryMode", &getDiscoveryModeRequest{}, &getDiscoveryModeResponse{})
+	services.Register(tdsNamespace, "GetScopes", &getScopesRequest{}, &getScopesResponse{})
+	services.Register(tdsNamespace, "GetHostname", &getHostnameRequest{}, &getHostnameResponse{})
+	services.Register(tdsNamespace, "GetDNS", &getDNSRequest{}, &getDNSResponse{})
+	services.Register(tdsNamespace, "SetDNS", &setDNSRequest{}, &setDNSResponse{})
+	services.Register(tdsNamespace, "GetNetworkInterfaces", &getNetworkInterfacesRequest{}, &getNetworkInterfacesResponse{})
+	services.Register(tdsNamespace, "SetNetworkInterfaces", &setNetworkInterfacesRequest{}, &setNetworkInterfacesResponse{})
+	services.Register(tdsNamespace, "GetNTP", &getNTPRequest{}, &getNTPResponse{})
+	services.Register(tdsNamespace, "SetNTP", &setNTPRequest{}, &setNTPResponse{})
+	services.Register(tdsNamespace, "GetUsers", &getUsersRequest{}, &getUsersResponse{})
+	services.Register(tdsNamespace, "CreateUsers", &createUsersRequest{}, &createUsersResponse{})
+	services.Register(tdsNamespace, "DeleteUsers", &deleteUsersRequest{}, &deleteUsersResponse{})
+	services.Register(tdsNamespace, "SetUser", &setUserRequest{}, &setUserResponse{})
+	services.Register(tdsNamespace, "AddScopes", &addScopesRequest{}, &addScopesResponse{})
+	services.Register(tdsNamespace, "RemoveScopes", &removeScopesRequest{}, &removeScopesResponse{})
+	services.Register(tdsNamespace, "SetScopes", &setScopesRequest{}, &setScopesResponse{})
+	services.Register(tdsNamespace, "SetHostname", &setHostnameRequest{}, &setHostnameResponse{})
+	services.Register(tdsNamespace, "SetSystemDateAndTime", &setSystemDateAndTimeRequest{}, &setSystemDateAndTimeResponse{})
+	services.Register(tdsNamespace, "SystemReboot", &systemRebootRequest{}, &systemRebootResponse{})
+	services.Register(tdsNamespace, "SetDiscoveryMode", &setDiscoveryModeRequest{}, &setDiscoveryModeResponse{})
+}
+
 var deviceXMLNs = []string{
 	`xmlns:tds="http://www.onvif.org/ver10/device/wsdl"`,
 	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
 }
 
+type getDeviceInformationRequest struct {
+	XMLName xml.Name `xml:"tds:GetDeviceInformation"`
+}
+
 // GetDeviceInformation fetch information of ONVIF camera
 func (device Device) GetDeviceInformation() (DeviceInformation, error) {
-	// Create SOAP
-	soap := SOAP{
-		Body:  "<tds:GetDeviceInformation/>",
-		XMLNs: deviceXMLNs,
-	}
-
-	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetDeviceInformation", device.SecurityHeader(), nil)
 	if err != nil {
 		return DeviceInformation{}, err
 	}
 
-	// Parse response to interface
-	deviceInfo, err := response.ValueForPath("Envelope.Body.GetDeviceInformationResponse")
-	if err != nil {
-		return DeviceInformation{}, err
-	}
+	return respVal.Interface().(DeviceInformation), nil
+}
 
-	// Parse interface to struct
-	result := DeviceInformation{}
-	err = interfaceToStruct(&deviceInfo, &result)
-	if err != nil {
-		return result, err
-	}
+type getSystemDateAndTimeRequest struct {
+	XMLName xml.Name `xml:"tds:GetSystemDateAndTime"`
+}
 
-	return result, nil
+type getSystemDateAndTimeResponse struct {
+	SystemDateAndTime struct {
+		UTCDateTime string `xml:"UTCDateTime"`
+	} `xml:"SystemDateAndTime"`
 }
 
 // GetSystemDateAndTime fetch date and time from ONVIF camera
 func (device Device) GetSystemDateAndTime() (string, error) {
-	// Create SOAP
-	soap := SOAP{
-		Body:  "</tds:GetSystemDateAndTime>",
-		XMLNs: deviceXMLNs,
-	}
-
-	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetSystemDateAndTime", device.SecurityHeader(), nil)
 	if err != nil {
 		return "", err
 	}
 
-	// Parse response
-	dateTime, _ := response.ValueForPathString("Envelope.Body.GetSystemDateAndTimeResponse.SystemDateAndTime")
-	return dateTime, nil
+	resp := respVal.Interface().(getSystemDateAndTimeResponse)
+	return resp.SystemDateAndTime.UTCDateTime, nil
 }
 
 // GetCapabilities fetch info of ONVIF camera's capabilities
+//
+// Capability flags are a free-form, vendor-extensible set of elements
+// rather than a fixed schema, which doesn't fit the typed request/response
+// registry in package services; this method is kept on the legacy SOAP
+// path with map[string]interface{} traversal for that reason.
 func (device Device) GetCapabilities() (DeviceCapabilities, error) {
 	// Create SOAP
 	soap := SOAP{
-		XMLNs: deviceXMLNs,
-		Body:  `<tds:GetCapabilities></tds:Category></tds:GetCapabilities>`,
+		XMLNs:  deviceXMLNs,
+		Body:   `<tds:GetCapabilities></tds:Category></tds:GetCapabilities>`,
+		Header: device.SecurityHeader(),
 	}
 
 	// Send SOAP request
@@ -146,144 +167,387 @@ func (device Device) GetCapabilities() (DeviceCapabilities, error) {
 	return deviceCapabilities, nil
 }
 
+type getDiscoveryModeRequest struct {
+	XMLName xml.Name `xml:"tds:GetDiscoveryMode"`
+}
+
+type getDiscoveryModeResponse struct {
+	DiscoveryMode string `xml:"DiscoveryMode"`
+}
+
 // GetDiscoveryMode fetch network discovery mode of an ONVIF camera
 func (device Device) GetDiscoveryMode() (string, error) {
-	// Create SOAP
-	soap := SOAP{
-		Body:  "<tds:GetDiscoveryMode/>",
-		XMLNs: deviceXMLNs,
-	}
-
-	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetDiscoveryMode", device.SecurityHeader(), nil)
 	if err != nil {
 		return "", err
 	}
 
-	// Parse response
-	discoveryMode, _ := response.ValueForPathString("Envelope.Body.GetDiscoveryModeResponse.DiscoveryMode")
-	return discoveryMode, nil
+	return respVal.Interface().(getDiscoveryModeResponse).DiscoveryMode, nil
+}
+
+type getScopesRequest struct {
+	XMLName xml.Name `xml:"tds:GetScopes"`
+}
+
+type scopeEntry struct {
+	ScopeDef  string `xml:"ScopeDef"`
+	ScopeItem string `xml:"ScopeItem"`
+}
+
+type getScopesResponse struct {
+	Scopes []scopeEntry `xml:"Scopes"`
 }
 
 // GetScopes fetch scopes of an ONVIF camera
 func (device Device) GetScopes() ([]string, error) {
-	// Create SOAP
-	soap := SOAP{
-		Body:  "<tds:GetScopes/>",
-		XMLNs: deviceXMLNs,
-	}
-
-	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetScopes", device.SecurityHeader(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse response to interface
-	ifaceScopes, err := response.ValuesForPath("Envelope.Body.GetScopesResponse.Scopes")
-	if err != nil {
-		return nil, err
-	}
+	resp := respVal.Interface().(getScopesResponse)
 
-	// Convert interface to array of scope
-	scopes := []string{}
-	for _, ifaceScope := range ifaceScopes {
-		if mapScope, ok := ifaceScope.(map[string]interface{}); ok {
-			scope := interfaceToString(mapScope["ScopeItem"])
-			scopes = append(scopes, scope)
-		}
+	scopes := make([]string, 0, len(resp.Scopes))
+	for _, entry := range resp.Scopes {
+		scopes = append(scopes, entry.ScopeItem)
 	}
 
 	return scopes, nil
 }
 
+type getHostnameRequest struct {
+	XMLName xml.Name `xml:"tds:GetHostname"`
+}
+
+type getHostnameResponse struct {
+	HostnameInformation HostnameInformation `xml:"HostnameInformation"`
+}
+
 // GetHostname fetch hostname of an ONVIF camera
 func (device Device) GetHostname() (HostnameInformation, error) {
-	// Create SOAP
-	soap := SOAP{
-		Body:  "<tds:GetHostname/>",
-		XMLNs: deviceXMLNs,
-	}
-
-	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetHostname", device.SecurityHeader(), nil)
 	if err != nil {
 		return HostnameInformation{}, err
 	}
 
-	// Parse response to interface
-	ifaceHostInfo, err := response.ValueForPath("Envelope.Body.GetHostnameResponse.HostnameInformation")
+	return respVal.Interface().(getHostnameResponse).HostnameInformation, nil
+}
+
+type getDNSRequest struct {
+	XMLName xml.Name `xml:"tds:GetDNS"`
+}
+
+type getDNSResponse struct {
+	DNSInformation DNSInformation `xml:"DNSInformation"`
+}
+
+// GetDNS fetch DNS configuration of an ONVIF camera
+func (device Device) GetDNS() (DNSInformation, error) {
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetDNS", device.SecurityHeader(), nil)
 	if err != nil {
-		return HostnameInformation{}, err
+		return DNSInformation{}, err
 	}
 
-	// Parse interface to struct
-	hostnameInfo := HostnameInformation{}
-	if mapHostInfo, ok := ifaceHostInfo.(map[string]interface{}); ok {
-		hostnameInfo.Name = interfaceToString(mapHostInfo["Name"])
-		hostnameInfo.FromDHCP = interfaceToBool(mapHostInfo["FromDHCP"])
-		hostnameInfo.Extension = interfaceToString(mapHostInfo["Extension"])
-	}
+	return respVal.Interface().(getDNSResponse).DNSInformation, nil
+}
 
-	return hostnameInfo, nil
+type setDNSRequest struct {
+	XMLName      xml.Name    `xml:"tds:SetDNS"`
+	FromDHCP     bool        `xml:"tds:FromDHCP"`
+	SearchDomain []string    `xml:"tds:SearchDomain,omitempty"`
+	DNSManual    []IPAddress `xml:"tds:DNSManual,omitempty"`
 }
 
-// GetDNS fetch DNS of an ONVIF camera
-func (device Device) GetDNS() (string, error) {
-	// Create SOAP
-	soap := SOAP{
-		Body:  "<tds:GetDNS/>",
-		XMLNs: deviceXMLNs,
-	}
+type setDNSResponse struct{}
+
+// SetDNS configure the DNS settings of an ONVIF camera
+func (device Device) SetDNS(dns DNSInformation) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "SetDNS", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setDNSRequest{
+			FromDHCP:     dns.FromDHCP,
+			SearchDomain: dns.SearchDomain,
+			DNSManual:    dns.DNSManual,
+		}))
+		return nil
+	})
+	return err
+}
 
-	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+type getNetworkInterfacesRequest struct {
+	XMLName xml.Name `xml:"tds:GetNetworkInterfaces"`
+}
+
+type getNetworkInterfacesResponse struct {
+	NetworkInterfaces []NetworkInterface `xml:"NetworkInterfaces"`
+}
+
+// GetNetworkInterfaces fetch the network interfaces of an ONVIF camera
+func (device Device) GetNetworkInterfaces() ([]NetworkInterface, error) {
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetNetworkInterfaces", device.SecurityHeader(), nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	bt, _ := response.JsonIndent("", "    ")
-	fmt.Println(string(bt))
+	return respVal.Interface().(getNetworkInterfacesResponse).NetworkInterfaces, nil
+}
 
-	// Parse response
-	DNS, _ := response.ValueForPathString("Envelope.Body.GetDNSResponse.DNSInformation")
-	return DNS, nil
+type setNetworkInterfacesRequest struct {
+	XMLName          xml.Name             `xml:"tds:SetNetworkInterfaces"`
+	InterfaceToken   string               `xml:"tds:InterfaceToken"`
+	NetworkInterface networkInterfaceBody `xml:"tds:NetworkInterface"`
 }
 
-// GetDNS fetch DNS of an ONVIF camera
-func (device Device) GetNetworkInterfaces() (string, error) {
-	// Create SOAP
-	soap := SOAP{
-		Body:  "<tds:GetNetworkInterfaces/>",
-		XMLNs: deviceXMLNs,
-	}
+type networkInterfaceBody struct {
+	Enabled bool                 `xml:"tt:Enabled"`
+	IPv4    NetworkInterfaceIPv4 `xml:"tt:IPv4"`
+}
 
-	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+type setNetworkInterfacesResponse struct{}
+
+// SetNetworkInterfaces configure a single network interface of an ONVIF camera
+func (device Device) SetNetworkInterfaces(token string, iface NetworkInterface) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "SetNetworkInterfaces", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setNetworkInterfacesRequest{
+			InterfaceToken: token,
+			NetworkInterface: networkInterfaceBody{
+				Enabled: iface.Enabled,
+				IPv4:    iface.IPv4,
+			},
+		}))
+		return nil
+	})
+	return err
+}
+
+type getNTPRequest struct {
+	XMLName xml.Name `xml:"tds:GetNTP"`
+}
+
+type getNTPResponse struct {
+	NTPInformation NTPInformation `xml:"NTPInformation"`
+}
+
+// GetNTP fetch the NTP configuration of an ONVIF camera
+func (device Device) GetNTP() (NTPInformation, error) {
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetNTP", device.SecurityHeader(), nil)
 	if err != nil {
-		return "", err
+		return NTPInformation{}, err
+	}
+
+	return respVal.Interface().(getNTPResponse).NTPInformation, nil
+}
+
+type setNTPRequest struct {
+	XMLName   xml.Name    `xml:"tds:SetNTP"`
+	FromDHCP  bool        `xml:"tds:FromDHCP"`
+	NTPManual []IPAddress `xml:"tds:NTPManual,omitempty"`
+}
+
+type setNTPResponse struct{}
+
+// SetNTP configure the NTP servers of an ONVIF camera
+func (device Device) SetNTP(fromDHCP bool, ntpServers []string) error {
+	manual := make([]IPAddress, 0, len(ntpServers))
+	for _, server := range ntpServers {
+		manual = append(manual, NewIPAddress("IPv4", server))
 	}
 
-	bt, _ := response.JsonIndent("", "    ")
-	fmt.Println(string(bt))
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "SetNTP", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setNTPRequest{FromDHCP: fromDHCP, NTPManual: manual}))
+		return nil
+	})
+	return err
+}
+
+type getUsersRequest struct {
+	XMLName xml.Name `xml:"tds:GetUsers"`
+}
 
-	// Parse response
-	DNS, _ := response.ValueForPathString("Envelope.Body.GetDNSResponse.DNSInformation")
-	return DNS, nil
+type getUsersResponse struct {
+	User []User `xml:"User"`
 }
 
-func interfaceToStruct(src, dst interface{}) error {
-	bt, err := json.Marshal(&src)
+// GetUsers fetch the list of users configured on an ONVIF camera
+func (device Device) GetUsers() ([]User, error) {
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "GetUsers", device.SecurityHeader(), nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = json.Unmarshal(bt, &dst)
+	return respVal.Interface().(getUsersResponse).User, nil
+}
+
+type createUsersRequest struct {
+	XMLName xml.Name `xml:"tds:CreateUsers"`
+	User    []User   `xml:"tds:User"`
+}
+
+type createUsersResponse struct{}
+
+// CreateUsers add new users to an ONVIF camera
+func (device Device) CreateUsers(users []User) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "CreateUsers", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(createUsersRequest{User: users}))
+		return nil
+	})
+	return err
+}
+
+type deleteUsersRequest struct {
+	XMLName  xml.Name `xml:"tds:DeleteUsers"`
+	Username []string `xml:"tds:Username"`
+}
+
+type deleteUsersResponse struct{}
+
+// DeleteUsers remove users from an ONVIF camera by username
+func (device Device) DeleteUsers(usernames []string) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "DeleteUsers", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(deleteUsersRequest{Username: usernames}))
+		return nil
+	})
+	return err
+}
+
+type setUserRequest struct {
+	XMLName xml.Name `xml:"tds:SetUser"`
+	User    User     `xml:"tds:User"`
+}
+
+type setUserResponse struct{}
+
+// SetUser update an existing user's password and/or access level
+func (device Device) SetUser(user User) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "SetUser", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setUserRequest{User: user}))
+		return nil
+	})
+	return err
+}
+
+type addScopesRequest struct {
+	XMLName   xml.Name `xml:"tds:AddScopes"`
+	ScopeItem []string `xml:"tds:ScopeItem"`
+}
+
+type addScopesResponse struct{}
+
+// AddScopes append additional scopes to an ONVIF camera
+func (device Device) AddScopes(scopes []string) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "AddScopes", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(addScopesRequest{ScopeItem: scopes}))
+		return nil
+	})
+	return err
+}
+
+type removeScopesRequest struct {
+	XMLName   xml.Name `xml:"tds:RemoveScopes"`
+	ScopeItem []string `xml:"tds:ScopeItem"`
+}
+
+type removeScopesResponse struct{}
+
+// RemoveScopes remove scopes from an ONVIF camera
+func (device Device) RemoveScopes(scopes []string) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "RemoveScopes", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(removeScopesRequest{ScopeItem: scopes}))
+		return nil
+	})
+	return err
+}
+
+type setScopesRequest struct {
+	XMLName xml.Name `xml:"tds:SetScopes"`
+	Scopes  []string `xml:"tds:Scopes"`
+}
+
+type setScopesResponse struct{}
+
+// SetScopes replace all scopes of an ONVIF camera
+func (device Device) SetScopes(scopes []string) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "SetScopes", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setScopesRequest{Scopes: scopes}))
+		return nil
+	})
+	return err
+}
+
+type setHostnameRequest struct {
+	XMLName xml.Name `xml:"tds:SetHostname"`
+	Name    string   `xml:"tds:Name"`
+}
+
+type setHostnameResponse struct{}
+
+// SetHostname set the hostname of an ONVIF camera
+func (device Device) SetHostname(name string) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "SetHostname", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setHostnameRequest{Name: name}))
+		return nil
+	})
+	return err
+}
+
+type setSystemDateAndTimeRequest struct {
+	XMLName         xml.Name `xml:"tds:SetSystemDateAndTime"`
+	DateTimeType    string   `xml:"tds:DateTimeType"`
+	DaylightSavings bool     `xml:"tds:DaylightSavings"`
+	TimeZone        struct {
+		TZ string `xml:"tt:TZ"`
+	} `xml:"tds:TimeZone"`
+	UTCDateTime string `xml:"tds:UTCDateTime"`
+}
+
+type setSystemDateAndTimeResponse struct{}
+
+// SetSystemDateAndTime set the system date, time and timezone of an ONVIF camera
+func (device Device) SetSystemDateAndTime(dateTimeType string, daylightSavings bool, timezone string, dateTime string) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "SetSystemDateAndTime", device.SecurityHeader(), func(req reflect.Value) error {
+		request := setSystemDateAndTimeRequest{
+			DateTimeType:    dateTimeType,
+			DaylightSavings: daylightSavings,
+			UTCDateTime:     dateTime,
+		}
+		request.TimeZone.TZ = timezone
+		req.Set(reflect.ValueOf(request))
+		return nil
+	})
+	return err
+}
+
+type systemRebootRequest struct {
+	XMLName xml.Name `xml:"tds:SystemReboot"`
+}
+
+type systemRebootResponse struct {
+	Message string `xml:"Message"`
+}
+
+// SystemReboot reboot an ONVIF camera and return the informational message it reports
+func (device Device) SystemReboot() (string, error) {
+	respVal, err := services.Dispatch(device.XAddr, tdsNamespace, "SystemReboot", device.SecurityHeader(), nil)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return respVal.Interface().(systemRebootResponse).Message, nil
+}
+
+type setDiscoveryModeRequest struct {
+	XMLName       xml.Name `xml:"tds:SetDiscoveryMode"`
+	DiscoveryMode string   `xml:"tds:DiscoveryMode"`
+}
+
+type setDiscoveryModeResponse struct{}
+
+// SetDiscoveryMode set the network discovery mode of an ONVIF camera
+func (device Device) SetDiscoveryMode(mode string) error {
+	_, err := services.Dispatch(device.XAddr, tdsNamespace, "SetDiscoveryMode", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setDiscoveryModeRequest{DiscoveryMode: mode}))
+		return nil
+	})
+	return err
 }
 
 func interfaceToString(src interface{}) string {
@@ -0,0 +1,251 @@
+// Package discovery implements WS-Discovery, the multicast probe/match
+// protocol ONVIF devices use to announce themselves on the local network.
+package discovery
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// multicastAddress is the well-known WS-Discovery multicast group and port
+// that every ONVIF device listens on.
+const multicastAddress = "239.255.255.250:3702"
+
+// probeTemplate is the SOAP envelope sent to discover NetworkVideoTransmitter
+// devices, per the ONVIF Core Specification.
+const probeTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<e:Envelope xmlns:e="http://www.w3.org/2003/05/soap-envelope"
+	xmlns:w="http://schemas.xmlsoap.org/ws/2004/08/addressing"
+	xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery"
+	xmlns:dn="http://www.onvif.org/ver10/network/wsdl">
+	<e:Header>
+		<w:MessageID>urn:uuid:%s</w:MessageID>
+		<w:To e:mustUnderstand="true">urn:schemas-xmlsoap-org:ws:2005:04:discovery</w:To>
+		<w:Action w:mustUnderstand="true">http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</w:Action>
+	</e:Header>
+	<e:Body>
+		<d:Probe>
+			<d:Types>dn:NetworkVideoTransmitter</d:Types>
+		</d:Probe>
+	</e:Body>
+</e:Envelope>`
+
+// ProbeMatch is a single device response to a WS-Discovery probe.
+type ProbeMatch struct {
+	EndpointUUID string
+	XAddrs       []string
+	Types        []string
+	Scopes       []string
+}
+
+// Event describes a device that was discovered or has departed, emitted by
+// Listen while it watches passive Hello/Bye announcements.
+type Event struct {
+	Kind  string // "Hello" or "Bye"
+	Match ProbeMatch
+}
+
+// Probe sends a WS-Discovery probe on the named network interface and
+// collects ProbeMatch responses until timeout elapses. Devices that reply
+// more than once (one XAddr per network interface is common) are
+// de-duplicated by endpoint UUID. The full ProbeMatch is returned for each
+// device, not just its XAddr, so callers can filter on Types/Scopes or
+// report EndpointUUID without re-parsing the wire message themselves.
+func Probe(ifaceName string, timeout time.Duration) ([]ProbeMatch, error) {
+	conn, err := newMulticastConn(ifaceName)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	messageID, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	probe := fmt.Sprintf(probeTemplate, messageID)
+
+	dst, err := net.ResolveUDPAddr("udp4", multicastAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = conn.WriteTo([]byte(probe), dst); err != nil {
+		return nil, fmt.Errorf("failed to send probe: %v", err)
+	}
+
+	if err = conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	matches := []ProbeMatch{}
+	buf := make([]byte, 65536)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+
+		match, err := parseProbeMatch(buf[:n])
+		if err != nil || seen[match.EndpointUUID] {
+			continue
+		}
+		seen[match.EndpointUUID] = true
+
+		if len(match.XAddrs) == 0 {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+// Listen runs a passive Hello/Bye listener on the named network interface
+// and emits an Event each time a device announces itself or departs. It
+// returns at once; a background goroutine reads announcements until stop
+// is closed, then closes the returned channel.
+func Listen(ifaceName string, stop <-chan struct{}) (<-chan Event, error) {
+	conn, err := newMulticastConn(ifaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer conn.Close()
+		defer close(events)
+
+		buf := make([]byte, 65536)
+		for {
+			_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+			n, _, err := conn.ReadFrom(buf)
+
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			if err != nil {
+				continue
+			}
+
+			kind := messageKind(buf[:n])
+			if kind == "" {
+				continue
+			}
+
+			match, err := parseProbeMatch(buf[:n])
+			if err != nil {
+				continue
+			}
+
+			events <- Event{Kind: kind, Match: match}
+		}
+	}()
+
+	return events, nil
+}
+
+// newMulticastConn opens a UDP socket bound to the named interface (or the
+// system default when ifaceName is empty) and joins the WS-Discovery
+// multicast group.
+func newMulticastConn(ifaceName string) (*net.UDPConn, error) {
+	group, err := net.ResolveUDPAddr("udp4", multicastAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var iface *net.Interface
+	if ifaceName != "" {
+		iface, err = net.InterfaceByName(ifaceName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find interface %s: %v", ifaceName, err)
+		}
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", iface, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on multicast group: %v", err)
+	}
+
+	return conn, nil
+}
+
+// parseProbeMatch extracts the fields we care about from a ProbeMatch (or
+// Hello/Bye) SOAP message without pulling in a full XML schema.
+func parseProbeMatch(data []byte) (ProbeMatch, error) {
+	match := ProbeMatch{}
+
+	match.EndpointUUID = extractBetween(data, "<wsa:Address>", "</wsa:Address>")
+	if match.EndpointUUID == "" {
+		match.EndpointUUID = extractBetween(data, "<a:Address>", "</a:Address>")
+	}
+	if match.EndpointUUID == "" {
+		return match, fmt.Errorf("no EndpointReference/Address found")
+	}
+
+	if xaddrs := extractBetween(data, "<d:XAddrs>", "</d:XAddrs>"); xaddrs != "" {
+		match.XAddrs = strings.Fields(xaddrs)
+	}
+	if types := extractBetween(data, "<d:Types>", "</d:Types>"); types != "" {
+		match.Types = strings.Fields(types)
+	}
+	if scopes := extractBetween(data, "<d:Scopes>", "</d:Scopes>"); scopes != "" {
+		match.Scopes = strings.Fields(scopes)
+	}
+
+	return match, nil
+}
+
+// messageKind returns "Hello" or "Bye" depending on which action the
+// message carries, or "" if it's neither.
+func messageKind(data []byte) string {
+	switch {
+	case bytes.Contains(data, []byte("/ws/2005/04/discovery/Hello")):
+		return "Hello"
+	case bytes.Contains(data, []byte("/ws/2005/04/discovery/Bye")):
+		return "Bye"
+	default:
+		return ""
+	}
+}
+
+// newUUID generates a random RFC 4122 version 4 UUID string, used as the
+// WS-Addressing MessageID on each probe.
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// extractBetween returns the text between the first occurrence of start and
+// the following occurrence of end, or "" if either is missing.
+func extractBetween(data []byte, start, end string) string {
+	s := string(data)
+	i := strings.Index(s, start)
+	if i == -1 {
+		return ""
+	}
+	s = s[i+len(start):]
+
+	j := strings.Index(s, end)
+	if j == -1 {
+		return ""
+	}
+
+	return strings.TrimSpace(s[:j])
+}
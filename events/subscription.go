@@ -0,0 +1,106 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/kcsraju/go-onvif/services"
+)
+
+func init() {
+	services.Register(tevNamespace, "CreatePullPointSubscription",
+		&createPullPointSubscriptionRequest{}, &createPullPointSubscriptionResponse{})
+	services.Register(tevNamespace, "PullMessages",
+		&pullMessagesRequest{}, &pullMessagesResponse{})
+	services.Register(tevNamespace, "Renew", &renewRequest{}, &renewResponse{})
+	services.Register(tevNamespace, "Unsubscribe", &unsubscribeRequest{}, &unsubscribeResponse{})
+}
+
+// Subscription is a live PullPoint subscription on an ONVIF device.
+type Subscription struct {
+	// Address is the SubscriptionReference the device returned; PullMessages,
+	// Renew and Unsubscribe are all sent to this address, not the device's
+	// original XAddr.
+	Address string
+
+	// Header is the WS-Security header (if any) used to create the
+	// subscription; PullMessages, Renew and Unsubscribe reuse it, since the
+	// SubscriptionReference is served by the same device and requires the
+	// same credentials.
+	Header string
+}
+
+// CreatePullPointSubscription opens a new PullPoint subscription on the
+// device at xaddr, filtered to topics (e.g. "tns1:VideoSource/MotionAlarm").
+// A nil or empty topics list subscribes to every topic the device emits.
+// initialTerminationTime is an ISO-8601 duration, e.g. "PT60S" for one
+// minute. header is the WS-Security header to authenticate the request
+// with, or "" for an unauthenticated device.
+func CreatePullPointSubscription(xaddr string, topics []string, initialTerminationTime, header string) (Subscription, error) {
+	respVal, err := services.Dispatch(xaddr, tevNamespace, "CreatePullPointSubscription", header,
+		func(req reflect.Value) error {
+			if len(topics) > 0 {
+				req.FieldByName("Filter").SetString(BuildTopicFilter(topics))
+			}
+			req.FieldByName("InitialTerminationTime").SetString(initialTerminationTime)
+			return nil
+		})
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	resp := respVal.Interface().(createPullPointSubscriptionResponse)
+	if resp.SubscriptionReference.Address == "" {
+		return Subscription{}, fmt.Errorf("events: device did not return a SubscriptionReference")
+	}
+
+	return Subscription{Address: resp.SubscriptionReference.Address, Header: header}, nil
+}
+
+// PullMessages long-polls the subscription for up to timeout (an ISO-8601
+// duration, e.g. "PT30S") for at most messageLimit notifications.
+func (s Subscription) PullMessages(timeout string, messageLimit int) ([]Event, error) {
+	respVal, err := services.Dispatch(s.Address, tevNamespace, "PullMessages", s.Header,
+		func(req reflect.Value) error {
+			req.FieldByName("Timeout").SetString(timeout)
+			req.FieldByName("MessageLimit").SetInt(int64(messageLimit))
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := respVal.Interface().(pullMessagesResponse)
+
+	events := make([]Event, 0, len(resp.NotificationMessage))
+	for _, msg := range resp.NotificationMessage {
+		data := make(map[string]string, len(msg.Message.Data.SimpleItem))
+		for _, item := range msg.Message.Data.SimpleItem {
+			data[item.Name] = item.Value
+		}
+
+		events = append(events, Event{
+			Topic:             msg.Topic,
+			ProducerReference: msg.ProducerReference.Address,
+			UTCTime:           msg.Message.UtcTime,
+			Data:              data,
+		})
+	}
+
+	return events, nil
+}
+
+// Renew extends the subscription's termination time.
+func (s Subscription) Renew(terminationTime string) error {
+	_, err := services.Dispatch(s.Address, tevNamespace, "Renew", s.Header, func(req reflect.Value) error {
+		req.FieldByName("TerminationTime").SetString(terminationTime)
+		return nil
+	})
+	return err
+}
+
+// Unsubscribe tears down the subscription.
+func (s Subscription) Unsubscribe() error {
+	_, err := services.Dispatch(s.Address, tevNamespace, "Unsubscribe", s.Header, nil)
+	return err
+}
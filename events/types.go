@@ -0,0 +1,110 @@
+// Package events implements the ONVIF PullPoint event subscription
+// pattern: creating a subscription, long-polling it for notifications,
+// renewing it, and tearing it down.
+package events
+
+import "encoding/xml"
+
+// tevNamespace is the WSDL namespace for the ONVIF event service.
+const tevNamespace = "tev"
+
+// SimpleItem is a single name/value pair carried by a notification's
+// message data, e.g. Name="State" Value="true" for a motion alarm.
+type SimpleItem struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:"Value,attr"`
+}
+
+// Event is a single parsed wsnt:NotificationMessage.
+type Event struct {
+	Topic             string
+	ProducerReference string
+	UTCTime           string
+	Data              map[string]string
+}
+
+// topicExpressionDialect is the only TopicExpression dialect ONVIF
+// devices are required to support.
+const topicExpressionDialect = "http://www.onvif.org/ver10/tev/topicExpression/ConcreteSet"
+
+// BuildTopicFilter serializes a list of topics (e.g.
+// "tns1:VideoSource/MotionAlarm") into the wsnt:Filter body expected by
+// CreatePullPointSubscription, using the ConcreteSet dialect.
+func BuildTopicFilter(topics []string) string {
+	expr := ""
+	for i, topic := range topics {
+		if i > 0 {
+			expr += " | "
+		}
+		expr += topic
+	}
+
+	return `<wsnt:Filter><wsnt:TopicExpression Dialect="` + topicExpressionDialect + `">` +
+		expr + `</wsnt:TopicExpression></wsnt:Filter>`
+}
+
+// createPullPointSubscriptionRequest is the tev:CreatePullPointSubscription
+// request body.
+type createPullPointSubscriptionRequest struct {
+	XMLName                xml.Name `xml:"tev:CreatePullPointSubscription"`
+	Filter                 string   `xml:",innerxml"`
+	InitialTerminationTime string   `xml:"tev:InitialTerminationTime,omitempty"`
+}
+
+// createPullPointSubscriptionResponse is the
+// tev:CreatePullPointSubscriptionResponse response body.
+type createPullPointSubscriptionResponse struct {
+	SubscriptionReference struct {
+		Address string `xml:"Address"`
+	} `xml:"SubscriptionReference"`
+	CurrentTime     string `xml:"CurrentTime"`
+	TerminationTime string `xml:"TerminationTime"`
+}
+
+// pullMessagesRequest is the tev:PullMessages request body.
+type pullMessagesRequest struct {
+	XMLName      xml.Name `xml:"tev:PullMessages"`
+	Timeout      string   `xml:"tev:Timeout"`
+	MessageLimit int      `xml:"tev:MessageLimit"`
+}
+
+// pullMessagesResponse is the tev:PullMessagesResponse response body.
+type pullMessagesResponse struct {
+	CurrentTime         string                `xml:"CurrentTime"`
+	TerminationTime     string                `xml:"TerminationTime"`
+	NotificationMessage []notificationMessage `xml:"NotificationMessage"`
+}
+
+// notificationMessage is a single wsnt:NotificationMessage entry.
+type notificationMessage struct {
+	Topic             string `xml:"Topic"`
+	ProducerReference struct {
+		Address string `xml:"Address"`
+	} `xml:"ProducerReference"`
+	Message struct {
+		UtcTime string `xml:"UtcTime,attr"`
+		Data    struct {
+			SimpleItem []SimpleItem `xml:"SimpleItem"`
+		} `xml:"Data"`
+	} `xml:"Message"`
+}
+
+// renewRequest is the wsnt:Renew request body.
+type renewRequest struct {
+	XMLName         xml.Name `xml:"wsnt:Renew"`
+	TerminationTime string   `xml:"wsnt:TerminationTime"`
+}
+
+// renewResponse is the wsnt:RenewResponse response body.
+type renewResponse struct {
+	CurrentTime     string `xml:"CurrentTime"`
+	TerminationTime string `xml:"TerminationTime"`
+}
+
+// unsubscribeRequest is the wsnt:Unsubscribe request body.
+type unsubscribeRequest struct {
+	XMLName xml.Name `xml:"wsnt:Unsubscribe"`
+}
+
+// unsubscribeResponse is the wsnt:UnsubscribeResponse response body.
+type unsubscribeResponse struct{}
@@ -0,0 +1,69 @@
+package onvif
+
+import (
+	"context"
+
+	"github.com/kcsraju/go-onvif/events"
+)
+
+// subscriptionLifetime is the termination time requested (and renewed)
+// for every subscription opened by SubscribeEvents.
+const subscriptionLifetime = "PT60S"
+
+// pullTimeout bounds each long-poll performed while streaming events.
+const pullTimeout = "PT30S"
+
+// SubscribeEvents opens a PullPoint subscription filtered to topics (e.g.
+// "tns1:VideoSource/MotionAlarm") and streams notifications on the
+// returned channel. It manages renewal and re-subscription in a
+// background goroutine, and closes the channel when ctx is cancelled.
+func (device Device) SubscribeEvents(ctx context.Context, topics []string) (<-chan events.Event, error) {
+	header := device.SecurityHeader()
+
+	sub, err := events.CreatePullPointSubscription(device.XAddr, topics, subscriptionLifetime, header)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan events.Event)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				sub.Unsubscribe()
+				return
+			default:
+			}
+
+			msgs, err := sub.PullMessages(pullTimeout, 64)
+			if err != nil {
+				sub, err = events.CreatePullPointSubscription(device.XAddr, topics, subscriptionLifetime, header)
+				if err != nil {
+					return
+				}
+				continue
+			}
+
+			if err = sub.Renew(subscriptionLifetime); err != nil {
+				sub, err = events.CreatePullPointSubscription(device.XAddr, topics, subscriptionLifetime, header)
+				if err != nil {
+					return
+				}
+			}
+
+			for _, msg := range msgs {
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+					sub.Unsubscribe()
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
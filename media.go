@@ -0,0 +1,218 @@
+package onvif
+
+import (
+	"encoding/xml"
+	"net/url"
+	"reflect"
+
+	"github.com/kcsraju/go-onvif/services"
+)
+
+const trtNamespace = "trt"
+
+func init() {
+	services.Register(trtNamespace, "GetProfiles", &getProfilesRequest{}, &getProfilesResponse{})
+	services.Register(trtNamespace, "GetStreamUri", &getStreamUriRequest{}, &getStreamUriResponse{})
+	services.Register(trtNamespace, "GetSnapshotUri", &getSnapshotUriRequest{}, &getSnapshotUriResponse{})
+	services.Register(trtNamespace, "GetVideoEncoderConfiguration", &getVideoEncoderConfigurationRequest{}, &getVideoEncoderConfigurationResponse{})
+	services.Register(trtNamespace, "SetVideoEncoderConfiguration", &setVideoEncoderConfigurationRequest{}, &setVideoEncoderConfigurationResponse{})
+}
+
+// VideoSourceConfiguration describes which physical video source a media
+// profile pulls from. The bounds applied to the source are not decoded.
+type VideoSourceConfiguration struct {
+	Token       string `xml:"token,attr"`
+	Name        string `xml:"Name"`
+	SourceToken string `xml:"SourceToken"`
+}
+
+// H264Options carries the H.264-specific settings of a video encoder
+// configuration.
+type H264Options struct {
+	GovLength   int    `xml:"GovLength"`
+	H264Profile string `xml:"H264Profile"`
+}
+
+// VideoResolution is a frame width/height pair.
+type VideoResolution struct {
+	Width  int `xml:"Width"`
+	Height int `xml:"Height"`
+}
+
+// VideoRateControl bounds a video encoder's frame rate, encoding interval
+// and bitrate.
+type VideoRateControl struct {
+	FrameRateLimit   int `xml:"FrameRateLimit"`
+	EncodingInterval int `xml:"EncodingInterval"`
+	BitrateLimit     int `xml:"BitrateLimit"`
+}
+
+// VideoEncoderConfiguration describes how a media profile encodes video:
+// codec, resolution and rate control.
+type VideoEncoderConfiguration struct {
+	Token       string           `xml:"token,attr"`
+	Name        string           `xml:"Name"`
+	Encoding    string           `xml:"Encoding"` // H264, H265 or JPEG
+	Resolution  VideoResolution  `xml:"Resolution"`
+	RateControl VideoRateControl `xml:"RateControl"`
+	H264        H264Options      `xml:"H264"`
+}
+
+// MediaProfile is a single configured media profile, bundling the video
+// source, encoder and PTZ configuration it uses.
+type MediaProfile struct {
+	Token                     string                    `xml:"token,attr"`
+	Name                      string                    `xml:"Name"`
+	VideoSourceConfiguration  VideoSourceConfiguration  `xml:"VideoSourceConfiguration"`
+	VideoEncoderConfiguration VideoEncoderConfiguration `xml:"VideoEncoderConfiguration"`
+	PTZConfiguration          PTZConfiguration          `xml:"PTZConfiguration"`
+}
+
+// StreamURI is a camera-provided stream address and how long it's valid
+// for without being re-requested.
+type StreamURI struct {
+	URI                 string `xml:"Uri"`
+	InvalidAfterConnect bool   `xml:"InvalidAfterConnect"`
+	InvalidAfterReboot  bool   `xml:"InvalidAfterReboot"`
+	Timeout             string `xml:"Timeout"`
+}
+
+type getProfilesRequest struct {
+	XMLName xml.Name `xml:"trt:GetProfiles"`
+}
+
+type getProfilesResponse struct {
+	Profiles []MediaProfile `xml:"Profiles"`
+}
+
+// GetProfiles fetch the media profiles configured on the camera
+func (device Device) GetProfiles() ([]MediaProfile, error) {
+	respVal, err := services.Dispatch(device.XAddr, trtNamespace, "GetProfiles", device.SecurityHeader(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return respVal.Interface().(getProfilesResponse).Profiles, nil
+}
+
+type streamSetup struct {
+	Stream    string `xml:"tt:Stream"`
+	Transport struct {
+		Protocol string `xml:"tt:Protocol"`
+	} `xml:"tt:Transport"`
+}
+
+type getStreamUriRequest struct {
+	XMLName      xml.Name    `xml:"trt:GetStreamUri"`
+	StreamSetup  streamSetup `xml:"trt:StreamSetup"`
+	ProfileToken string      `xml:"trt:ProfileToken"`
+}
+
+type getStreamUriResponse struct {
+	MediaUri StreamURI `xml:"MediaUri"`
+}
+
+// GetStreamUri fetch the stream address of a media profile for the given
+// transport protocol (UDP, HTTP or RTSP)
+func (device Device) GetStreamUri(profileToken, protocol string) (StreamURI, error) {
+	respVal, err := services.Dispatch(device.XAddr, trtNamespace, "GetStreamUri", device.SecurityHeader(), func(req reflect.Value) error {
+		request := getStreamUriRequest{ProfileToken: profileToken}
+		request.StreamSetup.Stream = "RTP-Unicast"
+		request.StreamSetup.Transport.Protocol = protocol
+		req.Set(reflect.ValueOf(request))
+		return nil
+	})
+	if err != nil {
+		return StreamURI{}, err
+	}
+
+	return respVal.Interface().(getStreamUriResponse).MediaUri, nil
+}
+
+type getSnapshotUriRequest struct {
+	XMLName      xml.Name `xml:"trt:GetSnapshotUri"`
+	ProfileToken string   `xml:"trt:ProfileToken"`
+}
+
+type getSnapshotUriResponse struct {
+	MediaUri StreamURI `xml:"MediaUri"`
+}
+
+// GetSnapshotUri fetch the JPEG snapshot address of a media profile
+func (device Device) GetSnapshotUri(profileToken string) (StreamURI, error) {
+	respVal, err := services.Dispatch(device.XAddr, trtNamespace, "GetSnapshotUri", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(getSnapshotUriRequest{ProfileToken: profileToken}))
+		return nil
+	})
+	if err != nil {
+		return StreamURI{}, err
+	}
+
+	return respVal.Interface().(getSnapshotUriResponse).MediaUri, nil
+}
+
+type getVideoEncoderConfigurationRequest struct {
+	XMLName            xml.Name `xml:"trt:GetVideoEncoderConfiguration"`
+	ConfigurationToken string   `xml:"trt:ConfigurationToken"`
+}
+
+type getVideoEncoderConfigurationResponse struct {
+	Configuration VideoEncoderConfiguration `xml:"Configuration"`
+}
+
+// GetVideoEncoderConfiguration fetch a single video encoder configuration
+// by its token
+func (device Device) GetVideoEncoderConfiguration(configToken string) (VideoEncoderConfiguration, error) {
+	respVal, err := services.Dispatch(device.XAddr, trtNamespace, "GetVideoEncoderConfiguration", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(getVideoEncoderConfigurationRequest{ConfigurationToken: configToken}))
+		return nil
+	})
+	if err != nil {
+		return VideoEncoderConfiguration{}, err
+	}
+
+	return respVal.Interface().(getVideoEncoderConfigurationResponse).Configuration, nil
+}
+
+type setVideoEncoderConfigurationRequest struct {
+	XMLName          xml.Name                  `xml:"trt:SetVideoEncoderConfiguration"`
+	Configuration    VideoEncoderConfiguration `xml:"trt:Configuration"`
+	ForcePersistence bool                      `xml:"trt:ForcePersistence"`
+}
+
+type setVideoEncoderConfigurationResponse struct{}
+
+// SetVideoEncoderConfiguration apply a video encoder configuration to the
+// camera
+func (device Device) SetVideoEncoderConfiguration(config VideoEncoderConfiguration) error {
+	_, err := services.Dispatch(device.XAddr, trtNamespace, "SetVideoEncoderConfiguration", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setVideoEncoderConfigurationRequest{
+			Configuration:    config,
+			ForcePersistence: true,
+		}))
+		return nil
+	})
+	return err
+}
+
+// RTSPURL fetch the RTSP stream address of a media profile with the
+// device's credentials injected, ready to hand to ffmpeg/gstreamer
+func (device Device) RTSPURL(profileToken string) (string, error) {
+	stream, err := device.GetStreamUri(profileToken, "RTSP")
+	if err != nil {
+		return "", err
+	}
+
+	if device.User == "" {
+		return stream.URI, nil
+	}
+
+	parsed, err := url.Parse(stream.URI)
+	if err != nil {
+		return "", err
+	}
+
+	parsed.User = url.UserPassword(device.User, device.Password)
+
+	return parsed.String(), nil
+}
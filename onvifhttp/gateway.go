@@ -0,0 +1,96 @@
+// Package onvifhttp exposes the services registry over plain HTTP, so the
+// library can be embedded as a REST bridge in front of ONVIF cameras for
+// clients that aren't Go.
+package onvifhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	onvif "github.com/kcsraju/go-onvif"
+	"github.com/kcsraju/go-onvif/discovery"
+	"github.com/kcsraju/go-onvif/services"
+)
+
+// Gateway is an http.Handler that forwards calls to a single camera's
+// SOAP endpoint through the services dispatcher.
+type Gateway struct {
+	// XAddr is the target camera's device service address.
+	XAddr string
+
+	// User and Password are the WS-Security credentials attached to every
+	// dispatched call. Leave both empty to call an unauthenticated device.
+	User     string
+	Password string
+
+	// DiscoveryInterface is the network interface GET /discovery probes
+	// on. An empty string uses the system default.
+	DiscoveryInterface string
+
+	// DiscoveryTimeout bounds how long GET /discovery waits for replies.
+	DiscoveryTimeout time.Duration
+}
+
+// securityHeader builds the WS-Security header for g's credentials, or ""
+// if none are configured.
+func (g Gateway) securityHeader() string {
+	return onvif.Device{User: g.User, Password: g.Password}.SecurityHeader()
+}
+
+// Handler returns the gateway's routes: POST /:service/:method dispatches
+// a SOAP call and GET /discovery probes the LAN for cameras.
+func (g Gateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/discovery", g.handleDiscovery)
+	mux.HandleFunc("/", g.handleCall)
+	return mux
+}
+
+// handleCall serves POST /:service/:method. The request body is decoded
+// as JSON into the registered request struct, dispatched over SOAP, and
+// the response struct is written back as JSON.
+func (g Gateway) handleCall(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 2 {
+		http.Error(w, "expected path /:service/:method", http.StatusBadRequest)
+		return
+	}
+	namespace, method := parts[0], parts[1]
+
+	resp, err := services.Dispatch(g.XAddr, namespace, method, g.securityHeader(), func(req reflect.Value) error {
+		return json.NewDecoder(r.Body).Decode(req.Addr().Interface())
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp.Interface())
+}
+
+// handleDiscovery serves GET /discovery by running a WS-Discovery probe
+// and returning the ProbeMatch details found as a JSON array.
+func (g Gateway) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	timeout := g.DiscoveryTimeout
+	if timeout == 0 {
+		timeout = 3 * time.Second
+	}
+
+	matches, err := discovery.Probe(g.DiscoveryInterface, timeout)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matches)
+}
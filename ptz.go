@@ -0,0 +1,408 @@
+package onvif
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/kcsraju/go-onvif/services"
+)
+
+const tptzNamespace = "tptz"
+
+func init() {
+	services.Register(tptzNamespace, "GetConfigurations", &getPTZConfigurationsRequest{}, &getPTZConfigurationsResponse{})
+	services.Register(tptzNamespace, "GetPresets", &getPresetsRequest{}, &getPresetsResponse{})
+	services.Register(tptzNamespace, "GotoPreset", &gotoPresetRequest{}, &gotoPresetResponse{})
+	services.Register(tptzNamespace, "SetPreset", &setPresetRequest{}, &setPresetResponse{})
+	services.Register(tptzNamespace, "RemovePreset", &removePresetRequest{}, &removePresetResponse{})
+	services.Register(tptzNamespace, "ContinuousMove", &continuousMoveRequest{}, &continuousMoveResponse{})
+	services.Register(tptzNamespace, "RelativeMove", &relativeMoveRequest{}, &relativeMoveResponse{})
+	services.Register(tptzNamespace, "AbsoluteMove", &absoluteMoveRequest{}, &absoluteMoveResponse{})
+	services.Register(tptzNamespace, "Stop", &stopRequest{}, &stopResponse{})
+	services.Register(tptzNamespace, "GetStatus", &getStatusRequest{}, &getStatusResponse{})
+}
+
+// velocityGenericSpace and positionGenericSpace are the PTZ spaces used
+// for ContinuousMove/RelativeMove and AbsoluteMove respectively. Those
+// methods always set them, overwriting any Space the caller supplied.
+const (
+	velocityGenericSpace = "http://www.onvif.org/ver10/tptz/PanTiltSpaces/VelocityGenericSpace"
+	positionGenericSpace = "http://www.onvif.org/ver10/tptz/PanTiltSpaces/PositionGenericSpace"
+	zoomVelocitySpace    = "http://www.onvif.org/ver10/tptz/ZoomSpaces/VelocityGenericSpace"
+	zoomPositionSpace    = "http://www.onvif.org/ver10/tptz/ZoomSpaces/PositionGenericSpace"
+)
+
+// Vector2D is a pan/tilt vector, used for both velocity (ContinuousMove,
+// RelativeMove) and position (AbsoluteMove) depending on which Space is
+// supplied.
+type Vector2D struct {
+	X     float32
+	Y     float32
+	Space string
+}
+
+// Vector1D is a zoom vector, used for both velocity and position
+// depending on which Space is supplied.
+type Vector1D struct {
+	X     float32
+	Space string
+}
+
+// PanTiltZoomVector bundles the pan/tilt and zoom components of a PTZ
+// move request.
+type PanTiltZoomVector struct {
+	PanTilt Vector2D
+	Zoom    Vector1D
+}
+
+// panTiltXML and zoomXML are the wire shapes of a PanTiltZoomVector's
+// components, each an element carrying x/y/space as attributes.
+type panTiltXML struct {
+	X     float32 `xml:"x,attr"`
+	Y     float32 `xml:"y,attr"`
+	Space string  `xml:"space,attr"`
+}
+
+type zoomXML struct {
+	X     float32 `xml:"x,attr"`
+	Space string  `xml:"space,attr"`
+}
+
+// ptzVectorXML is the wire shape shared by Velocity/Translation/Position,
+// each a tt:PanTilt / tt:Zoom pair.
+type ptzVectorXML struct {
+	PanTilt panTiltXML `xml:"tt:PanTilt"`
+	Zoom    zoomXML    `xml:"tt:Zoom"`
+}
+
+// toXML converts v to its wire shape for marshaling into a request.
+func (v PanTiltZoomVector) toXML() ptzVectorXML {
+	return ptzVectorXML{
+		PanTilt: panTiltXML{X: v.PanTilt.X, Y: v.PanTilt.Y, Space: v.PanTilt.Space},
+		Zoom:    zoomXML{X: v.Zoom.X, Space: v.Zoom.Space},
+	}
+}
+
+// fromXML converts a response's wire shape back into a PanTiltZoomVector.
+func (v ptzVectorXML) fromXML() PanTiltZoomVector {
+	return PanTiltZoomVector{
+		PanTilt: Vector2D{X: v.PanTilt.X, Y: v.PanTilt.Y, Space: v.PanTilt.Space},
+		Zoom:    Vector1D{X: v.Zoom.X, Space: v.Zoom.Space},
+	}
+}
+
+// PTZConfiguration describes a single PTZ configuration of a profile.
+type PTZConfiguration struct {
+	Token             string `xml:"token,attr"`
+	Name              string `xml:"Name"`
+	NodeToken         string `xml:"NodeToken"`
+	DefaultPTZSpeed   string `xml:"DefaultPTZSpeed"`
+	DefaultPTZTimeout string `xml:"DefaultPTZTimeout"`
+}
+
+// PTZPreset is a single stored preset position.
+type PTZPreset struct {
+	Token string `xml:"token,attr"`
+	Name  string `xml:"Name"`
+}
+
+// PTZStatus is the camera's current PTZ position and movement state.
+type PTZStatus struct {
+	Position   PanTiltZoomVector
+	MoveStatus map[string]string
+	Error      string
+	UTCTime    string
+}
+
+type getPTZConfigurationsRequest struct {
+	XMLName xml.Name `xml:"tptz:GetConfigurations"`
+}
+
+type getPTZConfigurationsResponse struct {
+	PTZConfiguration []PTZConfiguration `xml:"PTZConfiguration"`
+}
+
+// GetPTZConfigurations fetch the PTZ configurations available on the camera
+func (device Device) GetPTZConfigurations() ([]PTZConfiguration, error) {
+	respVal, err := services.Dispatch(device.XAddr, tptzNamespace, "GetConfigurations", device.SecurityHeader(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return respVal.Interface().(getPTZConfigurationsResponse).PTZConfiguration, nil
+}
+
+type getPresetsRequest struct {
+	XMLName      xml.Name `xml:"tptz:GetPresets"`
+	ProfileToken string   `xml:"tptz:ProfileToken"`
+}
+
+type getPresetsResponse struct {
+	Preset []PTZPreset `xml:"Preset"`
+}
+
+// GetPresets fetch the stored presets of a media profile
+func (device Device) GetPresets(profileToken string) ([]PTZPreset, error) {
+	respVal, err := services.Dispatch(device.XAddr, tptzNamespace, "GetPresets", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(getPresetsRequest{ProfileToken: profileToken}))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return respVal.Interface().(getPresetsResponse).Preset, nil
+}
+
+type gotoPresetRequest struct {
+	XMLName      xml.Name `xml:"tptz:GotoPreset"`
+	ProfileToken string   `xml:"tptz:ProfileToken"`
+	PresetToken  string   `xml:"tptz:PresetToken"`
+}
+
+type gotoPresetResponse struct{}
+
+// GotoPreset move the camera to a stored preset position
+func (device Device) GotoPreset(profileToken, presetToken string) error {
+	_, err := services.Dispatch(device.XAddr, tptzNamespace, "GotoPreset", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(gotoPresetRequest{ProfileToken: profileToken, PresetToken: presetToken}))
+		return nil
+	})
+	return err
+}
+
+type setPresetRequest struct {
+	XMLName      xml.Name `xml:"tptz:SetPreset"`
+	ProfileToken string   `xml:"tptz:ProfileToken"`
+	PresetToken  string   `xml:"tptz:PresetToken,omitempty"`
+	PresetName   string   `xml:"tptz:PresetName,omitempty"`
+}
+
+type setPresetResponse struct {
+	PresetToken string `xml:"PresetToken"`
+}
+
+// SetPreset store the camera's current position as a preset, creating a new
+// one if presetToken is empty, and returns the resulting preset token
+func (device Device) SetPreset(profileToken, presetToken, presetName string) (string, error) {
+	respVal, err := services.Dispatch(device.XAddr, tptzNamespace, "SetPreset", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(setPresetRequest{
+			ProfileToken: profileToken,
+			PresetToken:  presetToken,
+			PresetName:   presetName,
+		}))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return respVal.Interface().(setPresetResponse).PresetToken, nil
+}
+
+type removePresetRequest struct {
+	XMLName      xml.Name `xml:"tptz:RemovePreset"`
+	ProfileToken string   `xml:"tptz:ProfileToken"`
+	PresetToken  string   `xml:"tptz:PresetToken"`
+}
+
+type removePresetResponse struct{}
+
+// RemovePreset delete a stored preset from a media profile
+func (device Device) RemovePreset(profileToken, presetToken string) error {
+	_, err := services.Dispatch(device.XAddr, tptzNamespace, "RemovePreset", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(removePresetRequest{ProfileToken: profileToken, PresetToken: presetToken}))
+		return nil
+	})
+	return err
+}
+
+type continuousMoveRequest struct {
+	XMLName      xml.Name     `xml:"tptz:ContinuousMove"`
+	ProfileToken string       `xml:"tptz:ProfileToken"`
+	Velocity     ptzVectorXML `xml:"tptz:Velocity"`
+	Timeout      string       `xml:"tptz:Timeout"`
+}
+
+type continuousMoveResponse struct{}
+
+// ContinuousMove start a continuous pan/tilt/zoom move at the given
+// velocity until Stop is called or timeout elapses
+func (device Device) ContinuousMove(profileToken string, velocity PanTiltZoomVector, timeout time.Duration) error {
+	velocity.PanTilt.Space = velocityGenericSpace
+	velocity.Zoom.Space = zoomVelocitySpace
+
+	_, err := services.Dispatch(device.XAddr, tptzNamespace, "ContinuousMove", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(continuousMoveRequest{
+			ProfileToken: profileToken,
+			Velocity:     velocity.toXML(),
+			Timeout:      isoDuration(timeout),
+		}))
+		return nil
+	})
+	return err
+}
+
+type relativeMoveRequest struct {
+	XMLName      xml.Name     `xml:"tptz:RelativeMove"`
+	ProfileToken string       `xml:"tptz:ProfileToken"`
+	Translation  ptzVectorXML `xml:"tptz:Translation"`
+}
+
+type relativeMoveResponse struct{}
+
+// RelativeMove move the camera by a relative pan/tilt/zoom translation
+func (device Device) RelativeMove(profileToken string, translation PanTiltZoomVector) error {
+	translation.PanTilt.Space = velocityGenericSpace
+	translation.Zoom.Space = zoomVelocitySpace
+
+	_, err := services.Dispatch(device.XAddr, tptzNamespace, "RelativeMove", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(relativeMoveRequest{
+			ProfileToken: profileToken,
+			Translation:  translation.toXML(),
+		}))
+		return nil
+	})
+	return err
+}
+
+type absoluteMoveRequest struct {
+	XMLName      xml.Name     `xml:"tptz:AbsoluteMove"`
+	ProfileToken string       `xml:"tptz:ProfileToken"`
+	Position     ptzVectorXML `xml:"tptz:Position"`
+}
+
+type absoluteMoveResponse struct{}
+
+// AbsoluteMove move the camera to an absolute pan/tilt/zoom position
+func (device Device) AbsoluteMove(profileToken string, position PanTiltZoomVector) error {
+	position.PanTilt.Space = positionGenericSpace
+	position.Zoom.Space = zoomPositionSpace
+
+	_, err := services.Dispatch(device.XAddr, tptzNamespace, "AbsoluteMove", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(absoluteMoveRequest{
+			ProfileToken: profileToken,
+			Position:     position.toXML(),
+		}))
+		return nil
+	})
+	return err
+}
+
+type stopRequest struct {
+	XMLName      xml.Name `xml:"tptz:Stop"`
+	ProfileToken string   `xml:"tptz:ProfileToken"`
+	PanTilt      bool     `xml:"tptz:PanTilt"`
+	Zoom         bool     `xml:"tptz:Zoom"`
+}
+
+type stopResponse struct{}
+
+// Stop halt any ongoing pan/tilt/zoom movement on a media profile
+func (device Device) Stop(profileToken string) error {
+	_, err := services.Dispatch(device.XAddr, tptzNamespace, "Stop", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(stopRequest{ProfileToken: profileToken, PanTilt: true, Zoom: true}))
+		return nil
+	})
+	return err
+}
+
+type getStatusRequest struct {
+	XMLName      xml.Name `xml:"tptz:GetStatus"`
+	ProfileToken string   `xml:"tptz:ProfileToken"`
+}
+
+// ptzStatusVectorXML mirrors ptzVectorXML for decoding: encoding/xml
+// matches a tag's name against the element's local name only, so the
+// literal "tt:PanTilt"/"tt:Zoom" request tags would never match.
+type ptzStatusVectorXML struct {
+	PanTilt panTiltXML `xml:"PanTilt"`
+	Zoom    zoomXML    `xml:"Zoom"`
+}
+
+type getStatusResponse struct {
+	PTZStatus struct {
+		Position   ptzStatusVectorXML `xml:"Position"`
+		MoveStatus struct {
+			PanTilt string `xml:"PanTilt"`
+			Zoom    string `xml:"Zoom"`
+		} `xml:"MoveStatus"`
+		Error   string `xml:"Error"`
+		UTCTime string `xml:"UtcTime"`
+	} `xml:"PTZStatus"`
+}
+
+// GetStatus fetch the camera's current PTZ position and movement status
+func (device Device) GetStatus(profileToken string) (PTZStatus, error) {
+	respVal, err := services.Dispatch(device.XAddr, tptzNamespace, "GetStatus", device.SecurityHeader(), func(req reflect.Value) error {
+		req.Set(reflect.ValueOf(getStatusRequest{ProfileToken: profileToken}))
+		return nil
+	})
+	if err != nil {
+		return PTZStatus{}, err
+	}
+
+	resp := respVal.Interface().(getStatusResponse)
+	return PTZStatus{
+		Position: ptzVectorXML(resp.PTZStatus.Position).fromXML(),
+		MoveStatus: map[string]string{
+			"PanTilt": resp.PTZStatus.MoveStatus.PanTilt,
+			"Zoom":    resp.PTZStatus.MoveStatus.Zoom,
+		},
+		Error:   resp.PTZStatus.Error,
+		UTCTime: resp.PTZStatus.UTCTime,
+	}, nil
+}
+
+// isoDuration renders d as an ISO-8601 duration, e.g. "PT1.5S".
+//
+// strconv.FormatFloat with the 'f' verb is used instead of fmt's %g,
+// which switches to exponential notation for large values (e.g. "1e+06")
+// that ISO-8601 duration parsers reject.
+func isoDuration(d time.Duration) string {
+	return "PT" + strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+}
+
+// PanLeft pan the camera left at speed (0 to 1) for duration, then stop
+func (device Device) PanLeft(profileToken string, speed float32, duration time.Duration) error {
+	return device.jog(profileToken, PanTiltZoomVector{PanTilt: Vector2D{X: -speed}}, duration)
+}
+
+// PanRight pan the camera right at speed (0 to 1) for duration, then stop
+func (device Device) PanRight(profileToken string, speed float32, duration time.Duration) error {
+	return device.jog(profileToken, PanTiltZoomVector{PanTilt: Vector2D{X: speed}}, duration)
+}
+
+// TiltUp tilt the camera up at speed (0 to 1) for duration, then stop
+func (device Device) TiltUp(profileToken string, speed float32, duration time.Duration) error {
+	return device.jog(profileToken, PanTiltZoomVector{PanTilt: Vector2D{Y: speed}}, duration)
+}
+
+// TiltDown tilt the camera down at speed (0 to 1) for duration, then stop
+func (device Device) TiltDown(profileToken string, speed float32, duration time.Duration) error {
+	return device.jog(profileToken, PanTiltZoomVector{PanTilt: Vector2D{Y: -speed}}, duration)
+}
+
+// ZoomIn zoom the camera in at speed (0 to 1) for duration, then stop
+func (device Device) ZoomIn(profileToken string, speed float32, duration time.Duration) error {
+	return device.jog(profileToken, PanTiltZoomVector{Zoom: Vector1D{X: speed}}, duration)
+}
+
+// ZoomOut zoom the camera out at speed (0 to 1) for duration, then stop
+func (device Device) ZoomOut(profileToken string, speed float32, duration time.Duration) error {
+	return device.jog(profileToken, PanTiltZoomVector{Zoom: Vector1D{X: -speed}}, duration)
+}
+
+// jog wraps ContinuousMove with a timed Stop, so joystick-style UIs can
+// drive the camera with a single blocking call.
+func (device Device) jog(profileToken string, velocity PanTiltZoomVector, duration time.Duration) error {
+	if err := device.ContinuousMove(profileToken, velocity, duration); err != nil {
+		return err
+	}
+
+	time.Sleep(duration)
+
+	return device.Stop(profileToken)
+}
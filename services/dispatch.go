@@ -0,0 +1,145 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// namespaceURIs binds every namespace prefix used anywhere in the request
+// and response structs registered with this package to its WSDL/schema
+// URI. They're all declared on every envelope unconditionally: a request
+// struct's tags only ever spell out a literal "prefix:Local" element name,
+// encoding/xml never binds that prefix to anything, so the declaration has
+// to live on the envelope itself or the document is not namespace-valid.
+var namespaceURIs = map[string]string{
+	"tds":  "http://www.onvif.org/ver10/device/wsdl",
+	"trt":  "http://www.onvif.org/ver10/media/wsdl",
+	"tptz": "http://www.onvif.org/ver20/ptz/wsdl",
+	"timg": "http://www.onvif.org/ver20/imaging/wsdl",
+	"tev":  "http://www.onvif.org/ver10/events/wsdl",
+	"tan":  "http://www.onvif.org/ver20/analytics/wsdl",
+	"tt":   "http://www.onvif.org/ver10/schema",
+	"wsnt": "http://docs.oasis-open.org/wsn/b-2",
+	"wsa":  "http://www.w3.org/2005/08/addressing",
+}
+
+// envelopeXMLNs renders every entry of namespaceURIs as an xmlns
+// declaration, for the SOAP envelope root.
+func envelopeXMLNs() string {
+	decls := ""
+	for prefix, uri := range namespaceURIs {
+		decls += fmt.Sprintf(` xmlns:%s="%s"`, prefix, uri)
+	}
+	return decls
+}
+
+// envelopeTemplate wraps a marshaled request body in a SOAP 1.2 envelope
+// that declares every namespace prefix a request/response struct might
+// use, since the structs themselves only carry literal "prefix:Local"
+// element names rather than namespace-bound ones.
+const envelopeTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"%s>
+	<s:Header>%s</s:Header>
+	<s:Body>%s</s:Body>
+</s:Envelope>`
+
+// envelope is used to unwrap a SOAP response down to the raw contents of
+// its Body element, which is then unmarshaled a second time into the
+// registered response type. Matching "Body" by local name only (no
+// namespace given) works regardless of which prefix the server used.
+type envelope struct {
+	Body struct {
+		InnerXML []byte `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// soapFault is the SOAP 1.2 Fault element a device returns in place of the
+// expected "...Response" element to report an error (bad credentials,
+// unsupported operation, invalid parameter, etc). It's unmarshaled
+// straight from the same Body.InnerXML bytes as the response: the
+// required XMLName makes Unmarshal itself report a mismatch (rather than
+// silently succeeding with a zero value) when InnerXML's root isn't
+// actually a Fault.
+type soapFault struct {
+	XMLName xml.Name `xml:"Fault"`
+	Code    struct {
+		Value string `xml:"Value"`
+	} `xml:"Code"`
+	Reason struct {
+		Text string `xml:"Text"`
+	} `xml:"Reason"`
+}
+
+// Dispatch looks up namespace.method in the registry, lets fill populate
+// the zero-value request struct by reflection, sends the marshaled SOAP
+// envelope to xaddr, and returns the unmarshaled response value.
+//
+// fill may be nil, in which case the request is sent with its zero value.
+func Dispatch(xaddr, namespace, method, header string, fill func(req reflect.Value) error) (reflect.Value, error) {
+	m, ok := Lookup(namespace, method)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("services: no method registered for %s.%s", namespace, method)
+	}
+
+	reqPtr := reflect.New(m.ReqType)
+	if fill != nil {
+		if err := fill(reqPtr.Elem()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	reqBytes, err := xml.Marshal(reqPtr.Interface())
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("services: failed to marshal request: %v", err)
+	}
+
+	soapEnvelope := fmt.Sprintf(envelopeTemplate, envelopeXMLNs(), header, reqBytes)
+
+	httpResp, err := http.Post(xaddr, "application/soap+xml; charset=utf-8", bytes.NewBufferString(soapEnvelope))
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("services: failed to send request: %v", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBytes, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("services: failed to read response: %v", err)
+	}
+
+	// Unwrap down to the contents of <Body>, which is the single
+	// "...Response" element; encoding/xml matches a struct's fields
+	// against the immediate children of whatever root it's given, so
+	// unmarshaling the full envelope directly would look for response
+	// fields as children of Envelope rather than of the response element.
+	var env envelope
+	if err = xml.Unmarshal(respBytes, &env); err != nil {
+		if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+			return reflect.Value{}, fmt.Errorf("services: %s.%s failed with HTTP status %s", namespace, method, httpResp.Status)
+		}
+		return reflect.Value{}, fmt.Errorf("services: failed to unmarshal envelope: %v", err)
+	}
+
+	// A device reports an error by returning a Fault element in place of
+	// the expected response element; unmarshaling that straight into the
+	// response struct silently succeeds with a zero value, since none of
+	// its fields match, so Fault has to be checked for explicitly.
+	var fault soapFault
+	if xml.Unmarshal(env.Body.InnerXML, &fault) == nil {
+		return reflect.Value{}, fmt.Errorf("services: %s.%s fault: %s (%s)", namespace, method, fault.Reason.Text, fault.Code.Value)
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return reflect.Value{}, fmt.Errorf("services: %s.%s failed with HTTP status %s", namespace, method, httpResp.Status)
+	}
+
+	respPtr := reflect.New(m.RespType)
+	if err = xml.Unmarshal(env.Body.InnerXML, respPtr.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("services: failed to unmarshal response: %v", err)
+	}
+
+	return respPtr.Elem(), nil
+}
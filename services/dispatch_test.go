@@ -0,0 +1,81 @@
+package services
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type pingRequest struct {
+	XMLName xml.Name `xml:"test:Ping"`
+}
+
+type pingResponse struct {
+	Message string `xml:"Message"`
+}
+
+func init() {
+	Register("test", "Ping", &pingRequest{}, &pingResponse{})
+}
+
+func TestDispatchSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope">
+	<s:Body><test:PingResponse xmlns:test="urn:test"><Message>pong</Message></test:PingResponse></s:Body>
+</s:Envelope>`))
+	}))
+	defer server.Close()
+
+	respVal, err := Dispatch(server.URL, "test", "Ping", "", nil)
+	if err != nil {
+		t.Fatalf("Dispatch() error = %v, want nil", err)
+	}
+
+	resp := respVal.Interface().(pingResponse)
+	if resp.Message != "pong" {
+		t.Errorf("Dispatch() Message = %q, want %q", resp.Message, "pong")
+	}
+}
+
+func TestDispatchSOAPFault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope">
+	<s:Body>
+		<s:Fault>
+			<s:Code><s:Value>s:Sender</s:Value></s:Code>
+			<s:Reason><s:Text xml:lang="en">Not authorized</s:Text></s:Reason>
+		</s:Fault>
+	</s:Body>
+</s:Envelope>`))
+	}))
+	defer server.Close()
+
+	_, err := Dispatch(server.URL, "test", "Ping", "", nil)
+	if err == nil {
+		t.Fatal("Dispatch() error = nil, want a fault error")
+	}
+	if !strings.Contains(err.Error(), "Not authorized") {
+		t.Errorf("Dispatch() error = %q, want it to contain %q", err.Error(), "Not authorized")
+	}
+}
+
+func TestDispatchHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("upstream camera unreachable"))
+	}))
+	defer server.Close()
+
+	_, err := Dispatch(server.URL, "test", "Ping", "", nil)
+	if err == nil {
+		t.Fatal("Dispatch() error = nil, want an HTTP status error")
+	}
+	if !strings.Contains(err.Error(), "503") {
+		t.Errorf("Dispatch() error = %q, want it to mention the HTTP status", err.Error())
+	}
+}
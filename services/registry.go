@@ -0,0 +1,36 @@
+// Package services is a reflection-based SOAP method registry and
+// dispatcher. Instead of hand-writing a SOAP body and a response path for
+// every new ONVIF operation, a service registers its request/response
+// struct pair once, and Dispatch marshals/unmarshals both generically
+// using encoding/xml.
+package services
+
+import "reflect"
+
+// Method describes a single registered SOAP operation.
+type Method struct {
+	Namespace string
+	ReqType   reflect.Type
+	RespType  reflect.Type
+}
+
+var registry = map[string]Method{}
+
+// Register adds a service method to the registry, keyed as
+// "namespace.method" (e.g. "tds.GetDeviceInformation"). req and resp must
+// be pointers to zero-value request/response structs tagged for
+// encoding/xml with WSDL-derived element names; only their types are kept.
+func Register(namespace, method string, req, resp interface{}) {
+	registry[namespace+"."+method] = Method{
+		Namespace: namespace,
+		ReqType:   reflect.TypeOf(req).Elem(),
+		RespType:  reflect.TypeOf(resp).Elem(),
+	}
+}
+
+// Lookup returns the registered Method for namespace.method, and whether
+// one was found.
+func Lookup(namespace, method string) (Method, bool) {
+	m, ok := registry[namespace+"."+method]
+	return m, ok
+}
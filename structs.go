@@ -0,0 +1,101 @@
+package onvif
+
+// IPAddress represents a single statically or manually configured IP
+// address, used by both DNS servers and NTP servers. Type is "IPv4" or
+// "IPv6" and determines which of IPv4Address/IPv6Address is populated,
+// both on the wire and when building a request.
+type IPAddress struct {
+	Type        string `xml:"Type"`
+	IPv4Address string `xml:"IPv4Address,omitempty"`
+	IPv6Address string `xml:"IPv6Address,omitempty"`
+}
+
+// Address returns whichever of IPv4Address/IPv6Address applies to Type.
+func (addr IPAddress) Address() string {
+	if addr.Type == "IPv6" {
+		return addr.IPv6Address
+	}
+	return addr.IPv4Address
+}
+
+// NewIPAddress builds an IPAddress of the given type ("IPv4" or "IPv6"),
+// populating the matching field from address.
+func NewIPAddress(addrType, address string) IPAddress {
+	addr := IPAddress{Type: addrType}
+	if addrType == "IPv6" {
+		addr.IPv6Address = address
+	} else {
+		addr.IPv4Address = address
+	}
+	return addr
+}
+
+// DNSInformation represents the DNS configuration of an ONVIF camera.
+type DNSInformation struct {
+	FromDHCP     bool        `xml:"FromDHCP"`
+	SearchDomain []string    `xml:"SearchDomain"`
+	DNSManual    []IPAddress `xml:"DNSManual"`
+}
+
+// NetworkInterfaceInfo describes the hardware identity of a network
+// interface: its friendly name, MAC address and MTU.
+type NetworkInterfaceInfo struct {
+	Name      string `xml:"Name"`
+	HwAddress string `xml:"HwAddress"`
+	MTU       int    `xml:"MTU"`
+}
+
+// NetworkInterfaceLink describes the negotiated and admin link settings of
+// a network interface.
+type NetworkInterfaceLink struct {
+	AutoNegotiation bool   `xml:"AutoNegotiation"`
+	Speed           int    `xml:"Speed"`
+	Duplex          string `xml:"Duplex"`
+}
+
+// NetworkInterfaceIPv4Manual is a single manually assigned IPv4 address.
+type NetworkInterfaceIPv4Manual struct {
+	Address      string `xml:"Address"`
+	PrefixLength int    `xml:"PrefixLength"`
+}
+
+// NetworkInterfaceIPv4 represents the IPv4 configuration of a network
+// interface.
+type NetworkInterfaceIPv4 struct {
+	Enabled bool                       `xml:"Enabled"`
+	Manual  NetworkInterfaceIPv4Manual `xml:"Manual"`
+	DHCP    bool                       `xml:"DHCP"`
+}
+
+// NetworkInterfaceIPv6 represents the IPv6 configuration of a network
+// interface.
+type NetworkInterfaceIPv6 struct {
+	Enabled bool     `xml:"Enabled"`
+	Manual  []string `xml:"Manual"`
+	DHCP    string   `xml:"DHCP"`
+}
+
+// NetworkInterface represents a single network interface of an ONVIF
+// camera, as returned by GetNetworkInterfaces.
+type NetworkInterface struct {
+	Token   string               `xml:"token,attr"`
+	Enabled bool                 `xml:"Enabled"`
+	Info    NetworkInterfaceInfo `xml:"Info"`
+	Link    NetworkInterfaceLink `xml:"Link"`
+	IPv4    NetworkInterfaceIPv4 `xml:"IPv4"`
+	IPv6    NetworkInterfaceIPv6 `xml:"IPv6"`
+}
+
+// NTPInformation represents the NTP server configuration of an ONVIF
+// camera.
+type NTPInformation struct {
+	FromDHCP  bool        `xml:"FromDHCP"`
+	NTPManual []IPAddress `xml:"NTPManual"`
+}
+
+// User represents a single user account on an ONVIF camera.
+type User struct {
+	Username  string `xml:"Username"`
+	Password  string `xml:"Password,omitempty"`
+	UserLevel string `xml:"UserLevel"`
+}